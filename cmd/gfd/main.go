@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command gfd runs gpu-feature-discovery: it labels the node it runs on
+// with the GPU resources it discovers and exits.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/config"
+	"github.com/NVIDIA/gpu-feature-discovery/internal/lm"
+	"github.com/NVIDIA/gpu-feature-discovery/internal/nvml"
+	"github.com/NVIDIA/gpu-feature-discovery/internal/output"
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+func main() {
+	var migStrategy string
+	var mode string
+	var imexNodesConfigFile string
+	var outputFile string
+	var labelSinks cli.StringSlice
+
+	c := cli.NewApp()
+	c.Name = "gpu-feature-discovery"
+	c.Usage = "generate node labels for NVIDIA GPU resources"
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "mig-strategy",
+			Value:       lm.MigStrategyNone,
+			Usage:       "the MIG strategy to use: none, single, mixed, per-device",
+			Destination: &migStrategy,
+			EnvVars:     []string{"MIG_STRATEGY"},
+		},
+		&cli.StringFlag{
+			Name:        "mode",
+			Value:       lm.ModeNVML,
+			Usage:       "how to enumerate GPUs: nvml, vfio",
+			Destination: &mode,
+			EnvVars:     []string{"GFD_MODE"},
+		},
+		&cli.StringFlag{
+			Name:        "imex-nodes-config-file",
+			Usage:       "path to a file listing this node's IMEX peer IPs, one per line",
+			Destination: &imexNodesConfigFile,
+			EnvVars:     []string{"IMEX_NODES_CONFIG_FILE"},
+		},
+		&cli.StringFlag{
+			Name:        "output-file",
+			Value:       "/etc/kubernetes/node-feature-discovery/features.d/gfd",
+			Usage:       "path written to by the file label sink",
+			Destination: &outputFile,
+			EnvVars:     []string{"GFD_OUTPUT_FILE"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "label-sinks",
+			Value:       cli.NewStringSlice(output.SinkFile),
+			Usage:       "comma-separated list of destinations to write labels to: file, stdout, nfd-crd",
+			Destination: &labelSinks,
+			EnvVars:     []string{"GFD_LABEL_SINKS"},
+		},
+	}
+	c.Action = func(ctx *cli.Context) error {
+		if err := config.ValidateMigStrategy(migStrategy); err != nil {
+			return err
+		}
+		if err := config.ValidateMode(mode); err != nil {
+			return err
+		}
+		if err := config.ValidateLabelSinks(labelSinks.Value()); err != nil {
+			return err
+		}
+
+		return run(migStrategy, mode, imexNodesConfigFile, outputFile, labelSinks.Value())
+	}
+
+	if err := c.Run(os.Args); err != nil {
+		log.Fatalf("error running gpu-feature-discovery: %v", err)
+	}
+}
+
+func run(migStrategy, mode, imexNodesConfigFile, outputFile string, labelSinkNames []string) error {
+	specConfig := &spec.Config{
+		Flags: spec.Flags{
+			MigStrategy: &migStrategy,
+		},
+	}
+
+	gfdFlags := &lm.GFDFlags{
+		Mode: &mode,
+	}
+	if imexNodesConfigFile != "" {
+		gfdFlags.ImexNodesConfigFile = &imexNodesConfigFile
+	}
+
+	if mode != lm.ModeVFIO {
+		if err := nvml.Init(); err != nil {
+			return fmt.Errorf("failed to initialize NVML: %v", err)
+		}
+		defer nvml.Shutdown()
+	}
+
+	labeler, err := lm.NewResourceLabeler(nvml.New(), specConfig, gfdFlags)
+	if err != nil {
+		return fmt.Errorf("failed to construct labeler: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		return fmt.Errorf("failed to generate labels: %v", err)
+	}
+
+	sinks, err := output.NewSinks(labelSinkNames, outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to construct label sinks: %v", err)
+	}
+
+	if err := output.WriteAll(sinks, labels); err != nil {
+		return fmt.Errorf("failed to write labels: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package config validates the CLI flags gpu-feature-discovery was started
+// with before any labeler is constructed from them.
+package config
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/lm"
+	"github.com/NVIDIA/gpu-feature-discovery/internal/output"
+)
+
+// ValidateMigStrategy returns an error if migStrategy is not one of the
+// strategies gpu-feature-discovery knows how to label.
+func ValidateMigStrategy(migStrategy string) error {
+	switch migStrategy {
+	case lm.MigStrategyNone, lm.MigStrategySingle, lm.MigStrategyMixed, lm.MigStrategyPerDevice:
+		return nil
+	default:
+		return fmt.Errorf("invalid mig-strategy: %v", migStrategy)
+	}
+}
+
+// ValidateMode returns an error if mode is not a mode gpu-feature-discovery
+// knows how to enumerate GPUs with.
+func ValidateMode(mode string) error {
+	switch mode {
+	case lm.ModeNVML, lm.ModeVFIO:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode: %v", mode)
+	}
+}
+
+// ValidateLabelSinks returns an error if sinkNames contains a destination
+// gpu-feature-discovery does not know how to write labels to.
+func ValidateLabelSinks(sinkNames []string) error {
+	for _, name := range sinkNames {
+		switch name {
+		case output.SinkFile, output.SinkStdout, output.SinkNFDCRD:
+			continue
+		default:
+			return fmt.Errorf("invalid label sink: %v", name)
+		}
+	}
+	return nil
+}
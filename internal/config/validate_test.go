@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/output"
+)
+
+func TestValidateMigStrategy(t *testing.T) {
+	for _, valid := range []string{"none", "single", "mixed", "per-device"} {
+		if err := ValidateMigStrategy(valid); err != nil {
+			t.Errorf("expected %v to be valid, got error: %v", valid, err)
+		}
+	}
+
+	if err := ValidateMigStrategy("bogus"); err == nil {
+		t.Errorf("expected an error for an unknown mig-strategy")
+	}
+}
+
+func TestValidateMode(t *testing.T) {
+	for _, valid := range []string{"nvml", "vfio"} {
+		if err := ValidateMode(valid); err != nil {
+			t.Errorf("expected %v to be valid, got error: %v", valid, err)
+		}
+	}
+
+	if err := ValidateMode("bogus"); err == nil {
+		t.Errorf("expected an error for an unknown mode")
+	}
+}
+
+func TestValidateLabelSinks(t *testing.T) {
+	valid := []string{output.SinkFile, output.SinkStdout, output.SinkNFDCRD}
+	if err := ValidateLabelSinks(valid); err != nil {
+		t.Errorf("expected %v to be valid, got error: %v", valid, err)
+	}
+
+	if err := ValidateLabelSinks([]string{"bogus"}); err == nil {
+		t.Errorf("expected an error for an unknown label sink")
+	}
+}
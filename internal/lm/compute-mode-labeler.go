@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/nvml"
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// mixedModeLabel is reported for a mode label when devices on the node
+// disagree, rather than failing labeling altogether.
+const mixedModeLabel = "mixed"
+
+// newComputeModeLabeler creates a labeler reporting each GPU's compute mode
+// (e.g. Default, Exclusive_Process, Prohibited) and driver operating mode
+// (e.g. TCC/WDDM, or Graphics/Compute on GB/NVL parts). When devices on a
+// heterogeneous node disagree, the corresponding label falls back to
+// mixedModeLabel instead of being omitted.
+func newComputeModeLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error) {
+	count, err := nvmlLib.GetDeviceCount()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device count: %v", err)
+	}
+	if count == 0 {
+		return empty{}, nil
+	}
+
+	var computeModes, operatingModes []string
+	for i := uint(0); i < count; i++ {
+		device, err := nvmlLib.NewDevice(i)
+		if err != nil {
+			return nil, fmt.Errorf("error getting device: %v", err)
+		}
+
+		cm, err := device.GetComputeMode()
+		if err != nil {
+			return nil, fmt.Errorf("error getting compute mode for device %d: %v", i, err)
+		}
+		computeModes = append(computeModes, cm)
+
+		om, err := device.GetOperatingMode()
+		if err != nil {
+			return nil, fmt.Errorf("error getting operating mode for device %d: %v", i, err)
+		}
+		operatingModes = append(operatingModes, om)
+	}
+
+	labels := Labels{
+		"nvidia.com/gpu.compute-mode": modeLabel(computeModes),
+		"nvidia.com/gpu.mode":         modeLabel(operatingModes),
+	}
+
+	return labels, nil
+}
+
+// modeLabel returns the common value of modes, or mixedModeLabel if they
+// are not all identical. modes is assumed to be non-empty.
+func modeLabel(modes []string) string {
+	for _, m := range modes[1:] {
+		if m != modes[0] {
+			return mixedModeLabel
+		}
+	}
+	return modes[0]
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"testing"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+func TestModeLabelAgreement(t *testing.T) {
+	if got := modeLabel([]string{"Default", "Default", "Default"}); got != "Default" {
+		t.Fatalf("expected Default, got %v", got)
+	}
+}
+
+func TestModeLabelMismatchFallsBackToMixed(t *testing.T) {
+	if got := modeLabel([]string{"Default", "Exclusive_Process"}); got != mixedModeLabel {
+		t.Fatalf("expected %v, got %v", mixedModeLabel, got)
+	}
+}
+
+func TestModeLabelSingleDevice(t *testing.T) {
+	if got := modeLabel([]string{"TCC"}); got != "TCC" {
+		t.Fatalf("expected TCC, got %v", got)
+	}
+}
+
+func TestNewComputeModeLabelerAgreement(t *testing.T) {
+	nvmlLib := fakeNvml{devices: []fakeDevice{
+		{computeMode: "Default", operatingMode: "TCC"},
+		{computeMode: "Default", operatingMode: "TCC"},
+	}}
+
+	labeler, err := newComputeModeLabeler(nvmlLib, &spec.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if labels["nvidia.com/gpu.compute-mode"] != "Default" || labels["nvidia.com/gpu.mode"] != "TCC" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+}
+
+func TestNewComputeModeLabelerMismatchFallsBackToMixed(t *testing.T) {
+	nvmlLib := fakeNvml{devices: []fakeDevice{
+		{computeMode: "Default", operatingMode: "TCC"},
+		{computeMode: "Exclusive_Process", operatingMode: "TCC"},
+	}}
+
+	labeler, err := newComputeModeLabeler(nvmlLib, &spec.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if labels["nvidia.com/gpu.compute-mode"] != mixedModeLabel {
+		t.Fatalf("expected %v, got %v", mixedModeLabel, labels["nvidia.com/gpu.compute-mode"])
+	}
+}
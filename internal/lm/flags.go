@@ -0,0 +1,30 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+// GFDFlags holds gpu-feature-discovery-specific flags. These are kept
+// separate from spec.Flags (shared with k8s-device-plugin) because they
+// have no meaning outside of GFD.
+type GFDFlags struct {
+	// Mode selects how GPUs are enumerated: ModeNVML (the default) or
+	// ModeVFIO for hypervisor nodes passing GPUs through to VMs.
+	Mode *string
+	// ImexNodesConfigFile points at a file listing the IP addresses of this
+	// node's IMEX peers, used to derive a stable nvidia.com/gpu.imex-domain
+	// label.
+	ImexNodesConfigFile *string
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/nvml"
+)
+
+// newIMEXLabeler creates a labeler for IMEX domain and clique membership.
+// All GPUs on the node are required to report the same cluster UUID and
+// clique ID; on mismatch the labels are omitted entirely and a warning is
+// logged rather than failing labeling altogether.
+func newIMEXLabeler(nvmlLib nvml.Nvml, gfdFlags *GFDFlags) (Labeler, error) {
+	count, err := nvmlLib.GetDeviceCount()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device count: %v", err)
+	}
+	if count == 0 {
+		return empty{}, nil
+	}
+
+	var clusterUUID, cliqueID string
+	for i := uint(0); i < count; i++ {
+		device, err := nvmlLib.NewDevice(i)
+		if err != nil {
+			return nil, fmt.Errorf("error getting device: %v", err)
+		}
+
+		uuid, err := device.GetClusterUUID()
+		if err != nil {
+			return nil, fmt.Errorf("error getting cluster UUID for device %d: %v", i, err)
+		}
+
+		clique, err := device.GetCliqueID()
+		if err != nil {
+			return nil, fmt.Errorf("error getting clique ID for device %d: %v", i, err)
+		}
+
+		if i == 0 {
+			clusterUUID, cliqueID = uuid, clique
+			continue
+		}
+
+		if uuid != clusterUUID || clique != cliqueID {
+			log.Printf("WARNING: GPUs report inconsistent IMEX cluster/clique info; skipping nvidia.com/gpu.clique and nvidia.com/gpu.imex-domain labels")
+			return empty{}, nil
+		}
+	}
+
+	// A GPU that is not part of an IMEX domain reports an empty cluster UUID.
+	if clusterUUID == "" || cliqueID == "" {
+		return empty{}, nil
+	}
+
+	labels := Labels{
+		"nvidia.com/gpu.clique": fmt.Sprintf("%s.%s", clusterUUID, cliqueID),
+	}
+
+	domain, err := imexDomain(gfdFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine IMEX domain: %v", err)
+	}
+	if domain != "" {
+		labels["nvidia.com/gpu.imex-domain"] = domain
+	}
+
+	return labels, nil
+}
+
+// imexDomain derives a stable IMEX domain identifier for this node from the
+// set of peer node IPs listed in the file referenced by the
+// ImexNodesConfigFile flag. If no such file is configured, no domain can be
+// derived and an empty string is returned.
+func imexDomain(gfdFlags *GFDFlags) (string, error) {
+	if gfdFlags == nil || gfdFlags.ImexNodesConfigFile == nil || *gfdFlags.ImexNodesConfigFile == "" {
+		return "", nil
+	}
+
+	f, err := os.Open(*gfdFlags.ImexNodesConfigFile)
+	if err != nil {
+		return "", fmt.Errorf("error opening IMEX nodes config file %v: %v", *gfdFlags.ImexNodesConfigFile, err)
+	}
+	defer f.Close()
+
+	peers, err := parseImexPeers(f)
+	if err != nil {
+		return "", fmt.Errorf("error parsing IMEX nodes config file %v: %v", *gfdFlags.ImexNodesConfigFile, err)
+	}
+	if len(peers) == 0 {
+		return "", nil
+	}
+
+	return imexDomainFromPeers(peers), nil
+}
+
+// parseImexPeers reads one IP address per line from r, ignoring blank lines
+// and "#"-prefixed comments.
+func parseImexPeers(r *os.File) ([]string, error) {
+	var peers []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		ip := net.ParseIP(line)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IMEX peer IP: %v", line)
+		}
+
+		peers = append(peers, ip.String())
+	}
+
+	return peers, scanner.Err()
+}
+
+// imexDomainFromPeers derives a stable domain identifier from a set of peer
+// IPs. The peers are deduplicated and sorted before hashing so that nodes
+// sharing the same IMEX peer set always agree on the domain, regardless of
+// line order, duplicate entries, or whitespace in their copy of the config
+// file.
+func imexDomainFromPeers(peers []string) string {
+	unique := make(map[string]struct{}, len(peers))
+	for _, p := range peers {
+		unique[p] = struct{}{}
+	}
+
+	normalized := make([]string, 0, len(unique))
+	for p := range unique {
+		normalized = append(normalized, p)
+	}
+	sort.Strings(normalized)
+
+	sum := sha1.Sum([]byte(strings.Join(normalized, ",")))
+	return fmt.Sprintf("imex-%x", sum[:4])
+}
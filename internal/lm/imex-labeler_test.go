@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import "testing"
+
+func TestNewIMEXLabelerAgreement(t *testing.T) {
+	nvmlLib := fakeNvml{devices: []fakeDevice{
+		{clusterUUID: "cluster-a", cliqueID: "1"},
+		{clusterUUID: "cluster-a", cliqueID: "1"},
+	}}
+
+	labeler, err := newIMEXLabeler(nvmlLib, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if labels["nvidia.com/gpu.clique"] != "cluster-a.1" {
+		t.Fatalf("expected nvidia.com/gpu.clique=cluster-a.1, got %v", labels["nvidia.com/gpu.clique"])
+	}
+}
+
+func TestNewIMEXLabelerMismatchSkipsLabels(t *testing.T) {
+	nvmlLib := fakeNvml{devices: []fakeDevice{
+		{clusterUUID: "cluster-a", cliqueID: "1"},
+		{clusterUUID: "cluster-b", cliqueID: "2"},
+	}}
+
+	labeler, err := newIMEXLabeler(nvmlLib, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := labels["nvidia.com/gpu.clique"]; ok {
+		t.Fatalf("expected no clique label on mismatch, got %v", labels)
+	}
+}
+
+func TestNewIMEXLabelerEmptyClusterUUIDIsSkipped(t *testing.T) {
+	nvmlLib := fakeNvml{devices: []fakeDevice{
+		{clusterUUID: "", cliqueID: ""},
+	}}
+
+	labeler, err := newIMEXLabeler(nvmlLib, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels, err := labeler.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(labels) != 0 {
+		t.Fatalf("expected no labels for a device not part of an IMEX domain, got %v", labels)
+	}
+}
+
+func TestImexDomainFromPeersIsOrderAndDuplicateInsensitive(t *testing.T) {
+	a := imexDomainFromPeers([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3"})
+	b := imexDomainFromPeers([]string{"10.0.0.3", "10.0.0.1", "10.0.0.2", "10.0.0.1"})
+
+	if a != b {
+		t.Fatalf("expected domain to be stable across peer order and duplicates, got %v and %v", a, b)
+	}
+}
+
+func TestImexDomainFromPeersDiffersForDifferentPeers(t *testing.T) {
+	a := imexDomainFromPeers([]string{"10.0.0.1", "10.0.0.2"})
+	b := imexDomainFromPeers([]string{"10.0.0.1", "10.0.0.3"})
+
+	if a == b {
+		t.Fatalf("expected different peer sets to produce different domains, both got %v", a)
+	}
+}
+
+func TestParseImexPeersSkipsBlankLinesAndComments(t *testing.T) {
+	f, err := createTempFile(t, "10.0.0.1\n\n# a comment\n10.0.0.2\n")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer f.Close()
+
+	peers, err := parseImexPeers(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(peers) != 2 || peers[0] != "10.0.0.1" || peers[1] != "10.0.0.2" {
+		t.Fatalf("unexpected peers: %v", peers)
+	}
+}
+
+func TestParseImexPeersRejectsInvalidIP(t *testing.T) {
+	f, err := createTempFile(t, "not-an-ip\n")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := parseImexPeers(f); err == nil {
+		t.Fatalf("expected an error for an invalid IP, got none")
+	}
+}
@@ -19,6 +19,7 @@ package lm
 import (
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/NVIDIA/gpu-feature-discovery/internal/mig"
 	"github.com/NVIDIA/gpu-feature-discovery/internal/nvml"
@@ -27,9 +28,10 @@ import (
 
 // Constants representing different MIG strategies.
 const (
-	MigStrategyNone   = "none"
-	MigStrategySingle = "single"
-	MigStrategyMixed  = "mixed"
+	MigStrategyNone      = "none"
+	MigStrategySingle    = "single"
+	MigStrategyMixed     = "mixed"
+	MigStrategyPerDevice = "per-device"
 )
 
 // migResource is used to track MIG devices for labelling under the single and mixed strategies.
@@ -42,7 +44,11 @@ type migResource struct {
 
 // NewResourceLabeler creates a labeler for available GPU resources.
 // These include full GPU labels as well as labels specific to the mig-strategy specified.
-func NewResourceLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error) {
+func NewResourceLabeler(nvmlLib nvml.Nvml, config *spec.Config, gfdFlags *GFDFlags) (Labeler, error) {
+	if gfdFlags != nil && gfdFlags.Mode != nil && *gfdFlags.Mode == ModeVFIO {
+		return NewVFIOResourceLabeler(config)
+	}
+
 	count, err := nvmlLib.GetDeviceCount()
 	if err != nil {
 		return nil, fmt.Errorf("error getting device count: %v", err)
@@ -57,8 +63,18 @@ func NewResourceLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error)
 		return nil, fmt.Errorf("failed to construct GPU labeler: %v", err)
 	}
 
+	imexLabeler, err := newIMEXLabeler(nvmlLib, gfdFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct IMEX domain labeler: %v", err)
+	}
+
+	computeModeLabeler, err := newComputeModeLabeler(nvmlLib, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct compute-mode labeler: %v", err)
+	}
+
 	if *config.Flags.MigStrategy == spec.MigStrategyNone {
-		return fullGPULabeler, nil
+		return Merge(fullGPULabeler, imexLabeler, computeModeLabeler), nil
 	}
 
 	migLabeler, err := newMigLabeler(nvmlLib, config)
@@ -69,6 +85,8 @@ func NewResourceLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error)
 	labelers := Merge(
 		fullGPULabeler,
 		migLabeler,
+		imexLabeler,
+		computeModeLabeler,
 	)
 
 	return labelers, nil
@@ -96,6 +114,11 @@ func newMigLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create labeler for mig-strategy=mixed: %v", err)
 		}
+	case MigStrategyPerDevice:
+		labeler, err = newMigStrategyPerDeviceLabeler(nvmlLib, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create labeler for mig-strategy=per-device: %v", err)
+		}
 	default:
 		return nil, fmt.Errorf("unknown strategy: %v", *config.Flags.MigStrategy)
 	}
@@ -260,6 +283,86 @@ func newMigStrategyMixedLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler
 	return newMIGDeviceLabelers(resources, config)
 }
 
+// migStrategyPerDevice groups MIG devices by the index of the parent GPU they
+// belong to instead of requiring a single MIG profile across the whole node,
+// allowing heterogeneous MIG configurations to be labeled rather than
+// rejected as invalid.
+func newMigStrategyPerDeviceLabeler(nvmlLib nvml.Nvml, config *spec.Config) (Labeler, error) {
+	deviceInfo := mig.NewDeviceInfo(nvmlLib)
+
+	migs, err := deviceInfo.GetAllMigDevices()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve list of MIG devices: %v", err)
+	}
+
+	// profileCounts[gpuIndex][profile] tracks how many MIG devices of each
+	// profile are present under a given parent GPU.
+	profileCounts := make(map[uint]map[string]int)
+	for _, m := range migs {
+		parent, err := m.GetParentIndex()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine parent GPU index for MIG device: %v", err)
+		}
+
+		name, err := getMigDeviceName(m)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse MIG device name: %v", err)
+		}
+
+		if profileCounts[parent] == nil {
+			profileCounts[parent] = make(map[string]int)
+		}
+		profileCounts[parent][name]++
+	}
+
+	var labelers list
+	for gpuIndex, counts := range profileCounts {
+		// A single parent GPU can legitimately expose more than one MIG
+		// profile at once (e.g. one 3g.40gb plus two 1g.10gb instances on
+		// the same card). mig-profile/mig-count reflect the most common
+		// profile for compatibility with the single-profile case, but we
+		// also emit a per-profile count label for each profile present so
+		// that heterogeneous configurations are not silently collapsed.
+		profile, count := dominantMigProfile(counts)
+		if len(counts) > 1 {
+			log.Printf("WARNING: GPU %d exposes %d distinct MIG profiles; nvidia.com/gpu.%d.mig-profile reflects only the most common one (%s)", gpuIndex, len(counts), gpuIndex, profile)
+		}
+
+		labels := Labels{
+			fmt.Sprintf("nvidia.com/gpu.%d.mig-profile", gpuIndex): profile,
+			fmt.Sprintf("nvidia.com/gpu.%d.mig-count", gpuIndex):   fmt.Sprintf("%d", count),
+		}
+		for p, c := range counts {
+			labels[fmt.Sprintf("nvidia.com/gpu.%d.mig-%s.count", gpuIndex, p)] = fmt.Sprintf("%d", c)
+		}
+		labelers = append(labelers, labels)
+	}
+
+	return labelers, nil
+}
+
+// dominantMigProfile returns the profile with the highest count in counts.
+// Ties are broken by profile name so that the result is deterministic
+// across restarts rather than depending on Go's randomized map iteration
+// order.
+func dominantMigProfile(counts map[string]int) (string, int) {
+	profiles := make([]string, 0, len(counts))
+	for p := range counts {
+		profiles = append(profiles, p)
+	}
+	sort.Strings(profiles)
+
+	var profile string
+	var count int
+	for _, p := range profiles {
+		if counts[p] > count {
+			profile, count = p, counts[p]
+		}
+	}
+
+	return profile, count
+}
+
 func newMIGDeviceLabelers(resources map[string]migResource, config *spec.Config) (Labeler, error) {
 	var labelers list
 	for _, resource := range resources {
@@ -286,4 +389,4 @@ func getMigDeviceName(mig nvml.Device) (string, error) {
 	r := fmt.Sprintf("%dg.%dgb", g, gb)
 
 	return r, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import "testing"
+
+func TestDominantMigProfilePicksHighestCount(t *testing.T) {
+	profile, count := dominantMigProfile(map[string]int{"1g.10gb": 7, "3g.40gb": 2})
+	if profile != "1g.10gb" || count != 7 {
+		t.Fatalf("expected 1g.10gb/7, got %v/%v", profile, count)
+	}
+}
+
+func TestDominantMigProfileBreaksTiesDeterministically(t *testing.T) {
+	counts := map[string]int{"3g.40gb": 2, "1g.10gb": 2}
+
+	var first string
+	for i := 0; i < 10; i++ {
+		profile, count := dominantMigProfile(counts)
+		if count != 2 {
+			t.Fatalf("expected count 2, got %v", count)
+		}
+		if i == 0 {
+			first = profile
+			continue
+		}
+		if profile != first {
+			t.Fatalf("expected deterministic tie-break, got %v then %v", first, profile)
+		}
+	}
+
+	if first != "1g.10gb" {
+		t.Fatalf("expected lexicographically first profile 1g.10gb, got %v", first)
+	}
+}
+
+func TestDominantMigProfilePreservesAllCounts(t *testing.T) {
+	counts := map[string]int{"3g.40gb": 1, "1g.10gb": 2}
+
+	profile, count := dominantMigProfile(counts)
+	if profile != "1g.10gb" || count != 2 {
+		t.Fatalf("expected 1g.10gb/2, got %v/%v", profile, count)
+	}
+
+	// The caller is expected to still have access to every profile's count
+	// (e.g. to emit a per-profile label) even though dominantMigProfile only
+	// returns the winner.
+	if counts["3g.40gb"] != 1 {
+		t.Fatalf("expected the non-dominant profile's count to remain available, got %v", counts["3g.40gb"])
+	}
+}
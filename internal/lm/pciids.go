@@ -0,0 +1,30 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+// pciIDsDatabase maps "<vendorID>:<deviceID>" (lowercase hex, no "0x"
+// prefix) to a human readable product name. This is a minimal, bundled
+// subset of the NVIDIA entries in the canonical pci.ids database, covering
+// common passthrough targets; it is only consulted when NVML is unavailable.
+var pciIDsDatabase = map[string]string{
+	"10de:20b0": "A100-SXM4-40GB",
+	"10de:20b2": "A100-SXM4-80GB",
+	"10de:2230": "RTX A6000",
+	"10de:2236": "A10",
+	"10de:2684": "GeForce RTX 4090",
+	"10de:26b9": "L40S",
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/NVIDIA/gpu-feature-discovery/internal/nvml"
+)
+
+// createTempFile writes contents to a new temporary file and returns it
+// opened for reading. The file is removed when the test completes.
+func createTempFile(t *testing.T, contents string) (*os.File, error) {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "gfd-test-")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.WriteString(contents); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// fakeDevice is a fake nvml.Device backed entirely by its fields, for
+// exercising labelers without real NVML/hardware.
+type fakeDevice struct {
+	name          string
+	attributes    nvml.DeviceAttributes
+	clusterUUID   string
+	cliqueID      string
+	computeMode   string
+	operatingMode string
+	parentIndex   uint
+}
+
+func (d fakeDevice) GetName() (string, error)                      { return d.name, nil }
+func (d fakeDevice) GetAttributes() (nvml.DeviceAttributes, error) { return d.attributes, nil }
+func (d fakeDevice) GetClusterUUID() (string, error)               { return d.clusterUUID, nil }
+func (d fakeDevice) GetCliqueID() (string, error)                  { return d.cliqueID, nil }
+func (d fakeDevice) GetComputeMode() (string, error)               { return d.computeMode, nil }
+func (d fakeDevice) GetOperatingMode() (string, error)             { return d.operatingMode, nil }
+func (d fakeDevice) GetParentIndex() (uint, error)                 { return d.parentIndex, nil }
+
+// fakeNvml is a fake nvml.Nvml backed by an in-memory device list, for
+// exercising labelers without real NVML/hardware.
+type fakeNvml struct {
+	devices []fakeDevice
+}
+
+func (n fakeNvml) GetDeviceCount() (uint, error) {
+	return uint(len(n.devices)), nil
+}
+
+func (n fakeNvml) NewDevice(index uint) (nvml.Device, error) {
+	if index >= uint(len(n.devices)) {
+		return nil, fmt.Errorf("no device at index %d", index)
+	}
+	return n.devices[index], nil
+}
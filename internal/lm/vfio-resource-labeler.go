@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// Constants representing the supported GPU exposure modes.
+const (
+	ModeNVML = "nvml"
+	ModeVFIO = "vfio"
+)
+
+// vfioPCIDriverPath is the sysfs path exposing the PCI devices currently
+// bound to the vfio-pci driver.
+const vfioPCIDriverPath = "/sys/bus/pci/drivers/vfio-pci"
+
+// nvidiaPCIVendorID is the PCI vendor ID assigned to NVIDIA. Hypervisor
+// nodes commonly bind non-GPU devices (NICs, storage controllers, etc.) to
+// vfio-pci for passthrough as well, so vfioBoundDevices must filter on it.
+const nvidiaPCIVendorID = "10de"
+
+// vfioDevice describes a single GPU bound to vfio-pci on the host.
+type vfioDevice struct {
+	pciAddress string
+	vendorID   string
+	deviceID   string
+	iommuGroup string
+}
+
+// NewVFIOResourceLabeler creates a labeler for GPUs bound to vfio-pci on the
+// host. It walks vfioPCIDriverPath directly and does not require NVML to be
+// available in the host namespace, making it suitable for hypervisor nodes
+// that only pass GPUs through to VM workloads.
+func NewVFIOResourceLabeler(config *spec.Config) (Labeler, error) {
+	devices, err := vfioBoundDevices(vfioPCIDriverPath)
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating vfio-pci devices: %v", err)
+	}
+
+	if len(devices) == 0 {
+		return empty{}, nil
+	}
+
+	// GPUs passed through from the same node are assumed to be a single
+	// product; we use the first device found to resolve a product name.
+	product := resolvePCIProductName(devices[0].vendorID, devices[0].deviceID)
+
+	rl := resourceLabeler{
+		resourceName: "nvidia.com/gpu",
+	}
+
+	labels := rl.productLabel(product, "", "")
+	rl.updateLabel(labels, "count", len(devices))
+	labels["nvidia.com/gpu.passthrough"] = "true"
+
+	return labels, nil
+}
+
+// vfioBoundDevices walks driverPath and returns the set of PCI devices
+// currently bound to the vfio-pci driver.
+func vfioBoundDevices(driverPath string) ([]vfioDevice, error) {
+	entries, err := os.ReadDir(driverPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var devices []vfioDevice
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skip non PCI-address entries such as "bind", "unbind", "module".
+		if !strings.Contains(name, ":") {
+			continue
+		}
+
+		vendorID, err := readPCIHexAttribute(filepath.Join(driverPath, name, "vendor"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading vendor ID for %v: %v", name, err)
+		}
+		if vendorID != nvidiaPCIVendorID {
+			continue
+		}
+
+		deviceID, err := readPCIHexAttribute(filepath.Join(driverPath, name, "device"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading device ID for %v: %v", name, err)
+		}
+
+		iommuGroupPath, err := filepath.EvalSymlinks(filepath.Join(driverPath, name, "iommu_group"))
+		if err != nil {
+			return nil, fmt.Errorf("error resolving iommu group for %v: %v", name, err)
+		}
+
+		devices = append(devices, vfioDevice{
+			pciAddress: name,
+			vendorID:   vendorID,
+			deviceID:   deviceID,
+			iommuGroup: filepath.Base(iommuGroupPath),
+		})
+	}
+
+	return devices, nil
+}
+
+// readPCIHexAttribute reads a sysfs attribute file containing a single
+// "0x"-prefixed hex value and returns it without the prefix.
+func readPCIHexAttribute(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(raw)), "0x"), nil
+}
+
+// resolvePCIProductName resolves a human readable product name for the given
+// vendor/device ID pair using the bundled pci.ids subset, falling back to
+// the raw IDs if no match is found.
+func resolvePCIProductName(vendorID, deviceID string) string {
+	if name, ok := pciIDsDatabase[vendorID+":"+deviceID]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", vendorID, deviceID)
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePCIProductNameKnownDevice(t *testing.T) {
+	name := resolvePCIProductName("10de", "2236")
+	if name != "A10" {
+		t.Fatalf("expected A10, got %v", name)
+	}
+}
+
+func TestResolvePCIProductNameUnknownDeviceFallsBackToIDs(t *testing.T) {
+	name := resolvePCIProductName("10de", "ffff")
+	if name != "10de:ffff" {
+		t.Fatalf("expected fallback to raw IDs, got %v", name)
+	}
+}
+
+func TestVfioBoundDevicesMissingDriverPathReturnsNoDevices(t *testing.T) {
+	devices, err := vfioBoundDevices(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(devices) != 0 {
+		t.Fatalf("expected no devices, got %v", devices)
+	}
+}
+
+func TestVfioBoundDevicesParsesSysfsLayout(t *testing.T) {
+	root := t.TempDir()
+	devDir := filepath.Join(root, "0000:01:00.0")
+	if err := os.MkdirAll(devDir, 0755); err != nil {
+		t.Fatalf("failed to set up fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devDir, "vendor"), []byte("0x10de\n"), 0644); err != nil {
+		t.Fatalf("failed to write vendor: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devDir, "device"), []byte("0x2236\n"), 0644); err != nil {
+		t.Fatalf("failed to write device: %v", err)
+	}
+
+	iommuGroupTarget := filepath.Join(root, "iommu_groups", "7")
+	if err := os.MkdirAll(iommuGroupTarget, 0755); err != nil {
+		t.Fatalf("failed to set up fixture: %v", err)
+	}
+	if err := os.Symlink(iommuGroupTarget, filepath.Join(devDir, "iommu_group")); err != nil {
+		t.Fatalf("failed to symlink iommu_group: %v", err)
+	}
+
+	// Non PCI-address entries (e.g. "bind"/"unbind"/"module") must be skipped.
+	if err := os.WriteFile(filepath.Join(root, "bind"), []byte{}, 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	devices, err := vfioBoundDevices(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(devices) != 1 {
+		t.Fatalf("expected exactly one device, got %v", devices)
+	}
+
+	d := devices[0]
+	if d.pciAddress != "0000:01:00.0" || d.vendorID != "10de" || d.deviceID != "2236" || d.iommuGroup != "7" {
+		t.Fatalf("unexpected device: %+v", d)
+	}
+}
+
+func TestVfioBoundDevicesFiltersNonNVIDIAVendors(t *testing.T) {
+	root := t.TempDir()
+
+	writeVfioDeviceFixture(t, root, "0000:01:00.0", "0x10de", "0x2236", "7")
+	// A NIC also bound to vfio-pci for passthrough on the same hypervisor
+	// host must not be mistaken for a GPU.
+	writeVfioDeviceFixture(t, root, "0000:02:00.0", "0x8086", "0x1572", "8")
+
+	devices, err := vfioBoundDevices(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(devices) != 1 {
+		t.Fatalf("expected exactly one NVIDIA device, got %v", devices)
+	}
+	if devices[0].pciAddress != "0000:01:00.0" {
+		t.Fatalf("expected the NVIDIA device to be kept, got %v", devices[0])
+	}
+}
+
+// writeVfioDeviceFixture sets up a fake sysfs entry for a PCI device bound
+// to vfio-pci under root.
+func writeVfioDeviceFixture(t *testing.T, root, pciAddress, vendorID, deviceID, iommuGroup string) {
+	t.Helper()
+
+	devDir := filepath.Join(root, pciAddress)
+	if err := os.MkdirAll(devDir, 0755); err != nil {
+		t.Fatalf("failed to set up fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devDir, "vendor"), []byte(vendorID+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write vendor: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devDir, "device"), []byte(deviceID+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write device: %v", err)
+	}
+
+	iommuGroupTarget := filepath.Join(root, "iommu_groups", iommuGroup)
+	if err := os.MkdirAll(iommuGroupTarget, 0755); err != nil {
+		t.Fatalf("failed to set up fixture: %v", err)
+	}
+	if err := os.Symlink(iommuGroupTarget, filepath.Join(devDir, "iommu_group")); err != nil {
+		t.Fatalf("failed to symlink iommu_group: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nvml
+
+import (
+	"fmt"
+
+	gonvml "github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// nvmlLib is the real Nvml implementation, backed by go-nvml.
+type nvmlLib struct{}
+
+// New returns the real Nvml implementation, backed by go-nvml. NVML must
+// already be initialized (via Init()) before it is used.
+func New() Nvml {
+	return nvmlLib{}
+}
+
+// Init initializes NVML. It must be called once before the Nvml returned by
+// New is used, and Shutdown must be called once the caller is done with it.
+func Init() error {
+	if ret := gonvml.Init(); ret != gonvml.SUCCESS {
+		return fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return nil
+}
+
+// Shutdown releases the resources acquired by Init.
+func Shutdown() error {
+	if ret := gonvml.Shutdown(); ret != gonvml.SUCCESS {
+		return fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return nil
+}
+
+// GetDeviceCount implements the Nvml interface.
+func (nvmlLib) GetDeviceCount() (uint, error) {
+	count, ret := gonvml.DeviceGetCount()
+	if ret != gonvml.SUCCESS {
+		return 0, fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return uint(count), nil
+}
+
+// NewDevice implements the Nvml interface.
+func (nvmlLib) NewDevice(index uint) (Device, error) {
+	d, ret := gonvml.DeviceGetHandleByIndex(int(index))
+	if ret != gonvml.SUCCESS {
+		return nil, fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return device{d}, nil
+}
+
+// device is the real Device implementation, backed by a go-nvml device
+// handle. The same wrapper is used for both full GPUs and MIG devices.
+type device struct {
+	gonvml.Device
+}
+
+// GetName implements the Device interface.
+func (d device) GetName() (string, error) {
+	name, ret := d.Device.GetName()
+	if ret != gonvml.SUCCESS {
+		return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return name, nil
+}
+
+// GetAttributes implements the Device interface.
+func (d device) GetAttributes() (DeviceAttributes, error) {
+	attr, ret := d.Device.GetAttributes()
+	if ret != gonvml.SUCCESS {
+		return DeviceAttributes{}, fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return DeviceAttributes{
+		GpuInstanceSliceCount: attr.GpuInstanceSliceCount,
+		MemorySizeMB:          attr.MemorySizeMB,
+	}, nil
+}
+
+// GetClusterUUID implements the Device interface.
+func (d device) GetClusterUUID() (string, error) {
+	uuid, ret := d.Device.GetClusterUuid()
+	if ret == gonvml.ERROR_NOT_SUPPORTED {
+		return "", nil
+	}
+	if ret != gonvml.SUCCESS {
+		return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return uuid, nil
+}
+
+// GetCliqueID implements the Device interface.
+func (d device) GetCliqueID() (string, error) {
+	clique, ret := d.Device.GetCliqueId()
+	if ret == gonvml.ERROR_NOT_SUPPORTED {
+		return "", nil
+	}
+	if ret != gonvml.SUCCESS {
+		return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return fmt.Sprintf("%d", clique), nil
+}
+
+// GetComputeMode implements the Device interface.
+func (d device) GetComputeMode() (string, error) {
+	mode, ret := d.Device.GetComputeMode()
+	if ret != gonvml.SUCCESS {
+		return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return mode.String(), nil
+}
+
+// GetOperatingMode implements the Device interface.
+func (d device) GetOperatingMode() (string, error) {
+	model, ret := d.Device.GetDriverModel()
+	if ret == gonvml.ERROR_NOT_SUPPORTED {
+		// Platforms without a driver model concept (e.g. Linux outside of
+		// WSL) instead expose a Graphics/Compute display mode split.
+		displayMode, ret := d.Device.GetDisplayMode()
+		if ret != gonvml.SUCCESS {
+			return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+		}
+		if displayMode == gonvml.FEATURE_ENABLED {
+			return "Graphics", nil
+		}
+		return "Compute", nil
+	}
+	if ret != gonvml.SUCCESS {
+		return "", fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+	return model.String(), nil
+}
+
+// GetParentIndex implements the Device interface.
+func (d device) GetParentIndex() (uint, error) {
+	parent, ret := gonvml.DeviceGetDeviceHandleFromMigDeviceHandle(d.Device)
+	if ret == gonvml.ERROR_NOT_SUPPORTED || ret == gonvml.ERROR_INVALID_ARGUMENT {
+		// Not a MIG device: it is its own parent.
+		parent = d.Device
+	} else if ret != gonvml.SUCCESS {
+		return 0, fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+
+	index, ret := parent.GetIndex()
+	if ret != gonvml.SUCCESS {
+		return 0, fmt.Errorf("%v", gonvml.ErrorString(ret))
+	}
+
+	return uint(index), nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package nvml wraps the subset of the NVML API that gpu-feature-discovery
+// needs to enumerate devices and read the attributes it labels nodes with.
+package nvml
+
+// DeviceAttributes holds the subset of a device's MIG attributes used to
+// derive its canonical profile name.
+type DeviceAttributes struct {
+	GpuInstanceSliceCount uint32
+	MemorySizeMB          uint64
+}
+
+// Nvml is the interface for enumerating devices. NVML itself is initialized
+// by the package-level Init, not through this interface.
+type Nvml interface {
+	// GetDeviceCount returns the number of full GPUs visible on the node.
+	GetDeviceCount() (uint, error)
+	// NewDevice returns the device at the given index.
+	NewDevice(index uint) (Device, error)
+}
+
+// Device is the interface exposed by both full GPUs and MIG devices.
+type Device interface {
+	// GetName returns the product name of the device.
+	GetName() (string, error)
+	// GetAttributes returns the MIG attributes of the device.
+	GetAttributes() (DeviceAttributes, error)
+	// GetClusterUUID returns the UUID of the IMEX cluster the device belongs
+	// to, or an empty string if the device is not part of one.
+	GetClusterUUID() (string, error)
+	// GetCliqueID returns the ID of the IMEX clique (NVLink domain) the
+	// device belongs to, or an empty string if the device is not part of
+	// one.
+	GetCliqueID() (string, error)
+	// GetComputeMode returns the device's compute mode, e.g. "Default",
+	// "Exclusive_Process" or "Prohibited".
+	GetComputeMode() (string, error)
+	// GetOperatingMode returns the device's driver operating mode, e.g.
+	// "WDDM"/"TCC", or "Graphics"/"Compute" on platforms without a driver
+	// model.
+	GetOperatingMode() (string, error)
+	// GetParentIndex returns the node-level index of the full GPU a MIG
+	// device was created on. For a full GPU it returns its own index.
+	GetParentIndex() (uint, error)
+}
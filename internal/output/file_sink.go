@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileSink writes labels to the GFD feature-file, one `<key>=<value>` pair
+// per line. Writes are performed via a temporary file followed by a rename
+// so that node-feature-discovery's local source never observes a partially
+// written file.
+type FileSink struct {
+	path string
+}
+
+// NewFileSink creates a FileSink that writes to path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Write implements the Sink interface.
+func (s *FileSink) Write(labels map[string]string) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), "gfd-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary output file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := writeLabels(f, labels); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing temporary output file: %v", err)
+	}
+
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return fmt.Errorf("error renaming temporary output file to %v: %v", s.path, err)
+	}
+
+	return nil
+}
+
+// StdoutSink writes labels to stdout using the same line-protocol format as
+// FileSink, for scraping by external tooling.
+type StdoutSink struct{}
+
+// NewStdoutSink creates a StdoutSink.
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{}
+}
+
+// Write implements the Sink interface.
+func (s *StdoutSink) Write(labels map[string]string) error {
+	return writeLabels(os.Stdout, labels)
+}
+
+// writeLabels writes labels to w, one sorted `<key>=<value>` pair per line.
+func writeLabels(w io.Writer, labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", k, labels[k]); err != nil {
+			return fmt.Errorf("error writing label %v: %v", k, err)
+		}
+	}
+
+	return nil
+}
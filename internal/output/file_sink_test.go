@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkWriteRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gfd")
+
+	sink := NewFileSink(path)
+	labels := map[string]string{
+		"nvidia.com/gpu.count":   "2",
+		"nvidia.com/gpu.product": "A100",
+	}
+
+	if err := sink.Write(labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+
+	expected := "nvidia.com/gpu.count=2\nnvidia.com/gpu.product=A100\n"
+	if string(contents) != expected {
+		t.Errorf("expected %q, got %q", expected, string(contents))
+	}
+}
+
+func TestFileSinkWriteOverwritesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gfd")
+	if err := os.WriteFile(path, []byte("stale=true\n"), 0644); err != nil {
+		t.Fatalf("failed to seed output file: %v", err)
+	}
+
+	sink := NewFileSink(path)
+	if err := sink.Write(map[string]string{"fresh": "true"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+
+	if string(contents) != "fresh=true\n" {
+		t.Errorf("expected stale contents to be replaced, got %q", string(contents))
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+	nfdclient "sigs.k8s.io/node-feature-discovery/pkg/apis/generated/clientset/versioned"
+)
+
+// nfdNamespace is the namespace node-feature-discovery watches for
+// NodeFeature objects.
+const nfdNamespace = "node-feature-discovery"
+
+// NFDCRDSink writes labels to a node-feature-discovery NodeFeature custom
+// resource, for clusters that have moved from the feature-file protocol to
+// NFD's API-driven model.
+type NFDCRDSink struct {
+	client     nfdclient.Interface
+	nodeName   string
+	nodeUID    types.UID
+	objectName string
+}
+
+// NewNFDCRDSink constructs an NFDCRDSink using in-cluster config. The node
+// name is read from the NODE_NAME environment variable, which must be
+// populated from the downward API in the pod spec.
+func NewNFDCRDSink() (*NFDCRDSink, error) {
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error building in-cluster config: %v", err)
+	}
+
+	client, err := nfdclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error building node-feature-discovery client: %v", err)
+	}
+
+	coreClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error building core client: %v", err)
+	}
+
+	nodeName := os.Getenv("NODE_NAME")
+	if nodeName == "" {
+		return nil, fmt.Errorf("NODE_NAME environment variable must be set to use the %v sink", SinkNFDCRD)
+	}
+
+	node, err := coreClient.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting node %v: %v", nodeName, err)
+	}
+
+	return &NFDCRDSink{
+		client:     client,
+		nodeName:   nodeName,
+		nodeUID:    node.UID,
+		objectName: nodeName + "-gfd",
+	}, nil
+}
+
+// Write upserts a NodeFeature object owned by the node, setting spec.labels
+// to the given labels. The object is named "<node>-gfd" rather than after
+// the node alone so that it does not collide with nfd-worker's own
+// per-node NodeFeature object.
+func (s *NFDCRDSink) Write(labels map[string]string) error {
+	ctx := context.Background()
+	api := s.client.NfdV1alpha1().NodeFeatures(nfdNamespace)
+
+	ownerRefs := []metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Node",
+			Name:       s.nodeName,
+			UID:        s.nodeUID,
+		},
+	}
+
+	existing, err := api.Get(ctx, s.objectName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		nf := &nfdv1alpha1.NodeFeature{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            s.objectName,
+				Labels:          map[string]string{"nfd.node.kubernetes.io/node-name": s.nodeName},
+				OwnerReferences: ownerRefs,
+			},
+			Spec: nfdv1alpha1.NodeFeatureSpec{
+				Labels: labels,
+			},
+		}
+		if _, err := api.Create(ctx, nf, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating NodeFeature %v: %v", s.objectName, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting NodeFeature %v: %v", s.objectName, err)
+	}
+
+	existing.OwnerReferences = ownerRefs
+	existing.Spec.Labels = labels
+	if _, err := api.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating NodeFeature %v: %v", s.objectName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	nfdfake "sigs.k8s.io/node-feature-discovery/pkg/apis/generated/clientset/versioned/fake"
+)
+
+func newTestNFDCRDSink() (*NFDCRDSink, *nfdfake.Clientset) {
+	client := nfdfake.NewSimpleClientset()
+	return &NFDCRDSink{
+		client:     client,
+		nodeName:   "node-a",
+		nodeUID:    types.UID("node-a-uid"),
+		objectName: "node-a-gfd",
+	}, client
+}
+
+func TestNFDCRDSinkWriteCreatesOwnedObject(t *testing.T) {
+	sink, client := newTestNFDCRDSink()
+
+	if err := sink.Write(map[string]string{"nvidia.com/gpu.count": "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nf, err := client.NfdV1alpha1().NodeFeatures(nfdNamespace).Get(context.Background(), "node-a-gfd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected NodeFeature to have been created: %v", err)
+	}
+
+	if nf.Spec.Labels["nvidia.com/gpu.count"] != "1" {
+		t.Fatalf("unexpected labels: %v", nf.Spec.Labels)
+	}
+
+	if len(nf.OwnerReferences) != 1 || nf.OwnerReferences[0].Kind != "Node" || nf.OwnerReferences[0].Name != "node-a" || nf.OwnerReferences[0].UID != types.UID("node-a-uid") {
+		t.Fatalf("expected NodeFeature to be owned by the node, got %v", nf.OwnerReferences)
+	}
+}
+
+func TestNFDCRDSinkWriteUsesNodeSuffixedName(t *testing.T) {
+	sink, client := newTestNFDCRDSink()
+
+	if err := sink.Write(map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.NfdV1alpha1().NodeFeatures(nfdNamespace).Get(context.Background(), sink.nodeName, metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected no NodeFeature named exactly after the node, to avoid colliding with nfd-worker's own object")
+	}
+}
+
+func TestNFDCRDSinkWriteUpdatesExisting(t *testing.T) {
+	sink, client := newTestNFDCRDSink()
+
+	if err := sink.Write(map[string]string{"k": "v1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sink.Write(map[string]string{"k": "v2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nf, err := client.NfdV1alpha1().NodeFeatures(nfdNamespace).Get(context.Background(), "node-a-gfd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nf.Spec.Labels["k"] != "v2" {
+		t.Fatalf("expected labels to be updated, got %v", nf.Spec.Labels)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package output writes a completed set of GFD labels to one or more
+// destinations.
+package output
+
+import (
+	"fmt"
+)
+
+// Names of the sinks recognized by NewSinks, matching the values accepted by
+// the comma-separated --label-sinks flag.
+const (
+	SinkFile   = "file"
+	SinkStdout = "stdout"
+	SinkNFDCRD = "nfd-crd"
+)
+
+// Sink writes a completed set of node labels to a destination.
+type Sink interface {
+	Write(labels map[string]string) error
+}
+
+// NewSinks constructs the set of Sinks named in sinkNames, in order.
+// outputFilePath is only used by SinkFile.
+func NewSinks(sinkNames []string, outputFilePath string) ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range sinkNames {
+		switch name {
+		case SinkFile:
+			sinks = append(sinks, NewFileSink(outputFilePath))
+		case SinkStdout:
+			sinks = append(sinks, NewStdoutSink())
+		case SinkNFDCRD:
+			sink, err := NewNFDCRDSink()
+			if err != nil {
+				return nil, fmt.Errorf("failed to construct NFD NodeFeature sink: %v", err)
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown label sink: %v", name)
+		}
+	}
+
+	return sinks, nil
+}
+
+// WriteAll writes labels to every sink, returning the first error
+// encountered. Sinks after a failing one are still attempted so that a
+// single misbehaving sink does not prevent labels from reaching the others.
+func WriteAll(sinks []Sink, labels map[string]string) error {
+	var firstErr error
+	for _, sink := range sinks {
+		if err := sink.Write(labels); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
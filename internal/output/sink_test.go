@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2020-2022, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package output
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewSinksUnknownName(t *testing.T) {
+	if _, err := NewSinks([]string{"bogus"}, "/tmp/does-not-matter"); err == nil {
+		t.Fatalf("expected an error for an unknown label sink")
+	}
+}
+
+func TestNewSinksFileAndStdout(t *testing.T) {
+	sinks, err := NewSinks([]string{SinkFile, SinkStdout}, "/tmp/gfd-test-output")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sinks) != 2 {
+		t.Fatalf("expected 2 sinks, got %v", len(sinks))
+	}
+	if _, ok := sinks[0].(*FileSink); !ok {
+		t.Errorf("expected sinks[0] to be a *FileSink, got %T", sinks[0])
+	}
+	if _, ok := sinks[1].(*StdoutSink); !ok {
+		t.Errorf("expected sinks[1] to be a *StdoutSink, got %T", sinks[1])
+	}
+}
+
+type fakeSink struct {
+	err     error
+	written map[string]string
+}
+
+func (f *fakeSink) Write(labels map[string]string) error {
+	f.written = labels
+	return f.err
+}
+
+func TestWriteAllWritesToEverySink(t *testing.T) {
+	a, b := &fakeSink{}, &fakeSink{}
+	labels := map[string]string{"nvidia.com/gpu.count": "1"}
+
+	if err := WriteAll([]Sink{a, b}, labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a.written == nil || b.written == nil {
+		t.Fatalf("expected both sinks to receive labels")
+	}
+}
+
+func TestWriteAllReturnsFirstErrorButStillWritesToOthers(t *testing.T) {
+	failing := &fakeSink{err: fmt.Errorf("boom")}
+	ok := &fakeSink{}
+
+	err := WriteAll([]Sink{failing, ok}, map[string]string{"k": "v"})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if ok.written == nil {
+		t.Errorf("expected the sink after the failing one to still be written to")
+	}
+}